@@ -0,0 +1,66 @@
+// Package acl implements pluggable access control for calls to
+// controller.Service, modeled loosely on HashiCorp Consul's ACL
+// package: an ACL answers CanCall for a method name, and it travels
+// through a context.Context so Controller.One/Two can consult it
+// without threading an extra parameter through every call site.
+package acl
+
+import (
+	"context"
+	"errors"
+)
+
+// ACL decides whether the caller may invoke method.
+type ACL interface {
+	CanCall(method string) bool
+}
+
+// ErrPermissionDenied is returned by Controller.One/Two when the ACL
+// attached to ctx denies the call. It is distinguishable from the
+// existing "sorry, not that one" business error.
+var ErrPermissionDenied = errors.New("acl: permission denied")
+
+type allowAll struct{}
+
+func (allowAll) CanCall(string) bool { return true }
+
+// AllowAll is an ACL that permits every method.
+var AllowAll ACL = allowAll{}
+
+type denyAll struct{}
+
+func (denyAll) CanCall(string) bool { return false }
+
+// DenyAll is an ACL that denies every method.
+var DenyAll ACL = denyAll{}
+
+// StaticACL is an ACL backed by a fixed method -> allowed map, for
+// tests. A method absent from Allowed falls back to Default.
+type StaticACL struct {
+	Allowed map[string]bool
+	Default bool
+}
+
+func (a StaticACL) CanCall(method string) bool {
+	if allowed, ok := a.Allowed[method]; ok {
+		return allowed
+	}
+	return a.Default
+}
+
+type ctxKey struct{}
+
+// WithACL returns a context carrying a, for Controller.One/Two to
+// consult via FromContext.
+func WithACL(ctx context.Context, a ACL) context.Context {
+	return context.WithValue(ctx, ctxKey{}, a)
+}
+
+// FromContext returns the ACL attached to ctx by WithACL, or AllowAll
+// if none was attached.
+func FromContext(ctx context.Context) ACL {
+	if a, ok := ctx.Value(ctxKey{}).(ACL); ok {
+		return a
+	}
+	return AllowAll
+}
@@ -0,0 +1,66 @@
+package acl_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/acl"
+)
+
+func TestFromContextDefaultsToAllowAll(t *testing.T) {
+	if !acl.FromContext(context.Background()).CanCall("One") {
+		t.Fatal("expected a bare context to allow everything")
+	}
+}
+
+func TestWithACL(t *testing.T) {
+	ctx := acl.WithACL(context.Background(), acl.DenyAll)
+	if acl.FromContext(ctx).CanCall("One") {
+		t.Fatal("expected DenyAll to deny")
+	}
+}
+
+func TestStaticACL(t *testing.T) {
+	a := acl.StaticACL{Allowed: map[string]bool{"One": true}, Default: false}
+	if !a.CanCall("One") {
+		t.Fatal("expected One to be allowed")
+	}
+	if a.CanCall("Two") {
+		t.Fatal("expected Two to fall back to Default=false")
+	}
+}
+
+func TestPolicyACLFirstMatchWins(t *testing.T) {
+	a := acl.PolicyACL{
+		Rules: []acl.Rule{
+			{Method: "One", Allow: false},
+			{Method: "*", Allow: true},
+		},
+		Default: false,
+	}
+	if a.CanCall("One") {
+		t.Fatal("expected One to be denied by the specific rule")
+	}
+	if !a.CanCall("Two") {
+		t.Fatal("expected Two to be allowed by the glob rule")
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	a, err := acl.LoadPolicy([]byte(`[{"method":"One","policy":"deny"}]`), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a.CanCall("One") {
+		t.Fatal("expected One to be denied")
+	}
+	if !a.CanCall("Two") {
+		t.Fatal("expected Two to fall back to the default of true")
+	}
+}
+
+func TestLoadPolicyInvalidPolicy(t *testing.T) {
+	if _, err := acl.LoadPolicy([]byte(`[{"method":"One","policy":"maybe"}]`), true); err == nil {
+		t.Fatal("expected an error for an invalid policy value")
+	}
+}
@@ -0,0 +1,62 @@
+package acl
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+)
+
+// Rule is one entry in a PolicyACL's rule set: Method is a glob
+// (path.Match syntax, e.g. "One" or "*") matched against the called
+// method name, and Allow says whether matching methods are permitted.
+type Rule struct {
+	Method string
+	Allow  bool
+}
+
+// PolicyACL evaluates Rules in order and applies the first one whose
+// Method glob matches; Default applies if no rule matches.
+type PolicyACL struct {
+	Rules   []Rule
+	Default bool
+}
+
+func (a PolicyACL) CanCall(method string) bool {
+	for _, r := range a.Rules {
+		if ok, err := path.Match(r.Method, method); err == nil && ok {
+			return r.Allow
+		}
+	}
+	return a.Default
+}
+
+// rawRule is the JSON wire format of one PolicyACL rule, as loaded by
+// LoadPolicy: {"method": "One", "policy": "deny"}.
+type rawRule struct {
+	Method string `json:"method"`
+	Policy string `json:"policy"`
+}
+
+// LoadPolicy parses a JSON array of rawRule entries into a PolicyACL
+// with the given default. Each rule's "policy" field must be "allow"
+// or "deny".
+func LoadPolicy(data []byte, def bool) (PolicyACL, error) {
+	var raw []rawRule
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return PolicyACL{}, err
+	}
+	a := PolicyACL{Default: def}
+	for _, r := range raw {
+		var allow bool
+		switch r.Policy {
+		case "allow":
+			allow = true
+		case "deny":
+			allow = false
+		default:
+			return PolicyACL{}, fmt.Errorf("acl: invalid policy %q for method %q", r.Policy, r.Method)
+		}
+		a.Rules = append(a.Rules, Rule{Method: r.Method, Allow: allow})
+	}
+	return a, nil
+}
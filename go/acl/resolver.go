@@ -0,0 +1,58 @@
+package acl
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Resolver maps a caller token to the ACL that should gate its calls.
+type Resolver interface {
+	Resolve(ctx context.Context, token string) (ACL, error)
+}
+
+// ResolverFunc adapts a function to a Resolver.
+type ResolverFunc func(ctx context.Context, token string) (ACL, error)
+
+func (f ResolverFunc) Resolve(ctx context.Context, token string) (ACL, error) {
+	return f(ctx, token)
+}
+
+type cacheEntry struct {
+	acl     ACL
+	expires time.Time
+}
+
+// CachingResolver wraps a Resolver, caching each token's resolved ACL
+// for ttl so repeated calls with the same token don't all hit next.
+type CachingResolver struct {
+	next Resolver
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewCachingResolver returns a CachingResolver that delegates to next
+// and caches each result for ttl.
+func NewCachingResolver(next Resolver, ttl time.Duration) *CachingResolver {
+	return &CachingResolver{next: next, ttl: ttl, cache: map[string]cacheEntry{}}
+}
+
+func (r *CachingResolver) Resolve(ctx context.Context, token string) (ACL, error) {
+	r.mu.Lock()
+	entry, ok := r.cache[token]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.acl, nil
+	}
+
+	a, err := r.next.Resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.cache[token] = cacheEntry{acl: a, expires: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+	return a, nil
+}
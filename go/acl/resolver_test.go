@@ -0,0 +1,50 @@
+package acl_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/acl"
+)
+
+func TestCachingResolverCaches(t *testing.T) {
+	calls := 0
+	inner := acl.ResolverFunc(func(_ context.Context, token string) (acl.ACL, error) {
+		calls++
+		return acl.StaticACL{Default: token == "good"}, nil
+	})
+	resolver := acl.NewCachingResolver(inner, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		a, err := resolver.Resolve(context.Background(), "good")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !a.CanCall("One") {
+			t.Fatal("expected the resolved ACL to allow")
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected the resolver to be consulted once, got %d calls", calls)
+	}
+}
+
+func TestCachingResolverExpires(t *testing.T) {
+	calls := 0
+	inner := acl.ResolverFunc(func(_ context.Context, _ string) (acl.ACL, error) {
+		calls++
+		return acl.AllowAll, nil
+	})
+	resolver := acl.NewCachingResolver(inner, -time.Second)
+
+	if _, err := resolver.Resolve(context.Background(), "tok"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := resolver.Resolve(context.Background(), "tok"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected an already-expired entry to be refreshed, got %d calls", calls)
+	}
+}
@@ -1,43 +1,188 @@
 // Package device is a simple function-based wrapper around
-// `controller` that operates on a singleton. You must call Init
-// first, and then you can call the other functions, which call
-// methods on the singleton.
+// `controller` that operates on a singleton. You must call Init,
+// DialRPC, or InitGRPC first, and then you can call the other
+// functions, which call methods on the singleton.
 package device
 
 import (
+	"context"
 	"errors"
+	"log"
+	"net"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/acl"
+	"github.com/jberkenbilt/go-to-rust-blog/go/grpcclient"
 	"github.com/jberkenbilt/go-to-rust-blog/go/internal/controller"
+	"github.com/jberkenbilt/go-to-rust-blog/go/middleware"
+	"github.com/jberkenbilt/go-to-rust-blog/go/retry"
+	"github.com/jberkenbilt/go-to-rust-blog/go/rpc"
 )
 
-var theController *controller.Controller = nil
+var (
+	theController  controller.Service = nil
+	theACL         acl.ACL
+	theACLResolver acl.Resolver
+	theACLToken    string
+)
+
+// options collects the functional options passed to Init.
+type options struct {
+	middlewares    []middleware.Middleware
+	controllerOpts []controller.Option
+	acl            acl.ACL
+	aclResolver    acl.Resolver
+	aclToken       string
+}
+
+// Option configures the middleware chain Init builds around the
+// singleton.
+type Option func(*options)
+
+// WithLogger adds structured request logging to the middleware chain.
+func WithLogger(logger *log.Logger) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middleware.LoggingMiddleware(logger))
+	}
+}
+
+// WithMetrics adds Prometheus request count, latency, and sequence
+// gauge instrumentation to the middleware chain.
+func WithMetrics(requestCount *prometheus.CounterVec, requestLatency *prometheus.HistogramVec, seqGauge prometheus.Gauge) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middleware.InstrumentingMiddleware(requestCount, requestLatency, seqGauge))
+	}
+}
+
+// WithTracer adds an OpenTelemetry span per call to the middleware
+// chain.
+func WithTracer(tracer trace.Tracer) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middleware.TracingMiddleware(tracer))
+	}
+}
 
-// runMethod is a generic dispatcher that is used by the wrapper API
-// to call methods on the singleton. It takes a closure that takes a
-// *Controller and an arg, calls the closure using the singleton, and
+// WithRetry configures the retry policy the in-process controller uses
+// for its fake network call. It has no effect on DialRPC or InitGRPC,
+// which back the singleton with a remote controller instead.
+func WithRetry(policy retry.Policy) Option {
+	return func(o *options) {
+		o.controllerOpts = append(o.controllerOpts, controller.WithRetryPolicy(policy))
+	}
+}
+
+// WithACL attaches a static ACL to every call made through the
+// singleton. A Resolver set by WithACLResolver takes precedence if both
+// are configured.
+func WithACL(a acl.ACL) Option {
+	return func(o *options) {
+		o.acl = a
+	}
+}
+
+// WithACLResolver sets the Resolver that WithToken uses to map a token
+// to an ACL at call time.
+func WithACLResolver(resolver acl.Resolver) Option {
+	return func(o *options) {
+		o.aclResolver = resolver
+	}
+}
+
+// WithToken arranges for each call to resolve its ACL from token via
+// the Resolver set by WithACLResolver, overriding WithACL. It has no
+// effect unless WithACLResolver is also given.
+func WithToken(token string) Option {
+	return func(o *options) {
+		o.aclToken = token
+	}
+}
+
+// runMethod is a generic dispatcher that is used by the wrapper API to
+// call methods on the singleton. It takes a closure that takes the
+// singleton and an arg, calls the closure using the singleton, and
 // returns the result.
 func runMethod[ArgT any, ResultT any](
-	f func(*controller.Controller, ArgT) (ResultT, error),
+	ctx context.Context,
+	f func(context.Context, controller.Service, ArgT) (ResultT, error),
 	arg ArgT,
 ) (ResultT, error) {
 	if theController == nil {
 		var zero ResultT
 		return zero, errors.New("call Init first")
 	}
-	return f(theController, arg)
+	ctx, err := withACL(ctx)
+	if err != nil {
+		var zero ResultT
+		return zero, err
+	}
+	return f(ctx, theController, arg)
 }
 
-func Init() {
-	theController = controller.New()
+// withACL attaches the singleton's configured ACL to ctx: theACLToken
+// resolved via theACLResolver if one is set, else theACL directly, else
+// ctx is returned unchanged and Controller.One/Two fall back to
+// acl.AllowAll.
+func withACL(ctx context.Context) (context.Context, error) {
+	switch {
+	case theACLResolver != nil:
+		a, err := theACLResolver.Resolve(ctx, theACLToken)
+		if err != nil {
+			return ctx, err
+		}
+		return acl.WithACL(ctx, a), nil
+	case theACL != nil:
+		return acl.WithACL(ctx, theACL), nil
+	default:
+		return ctx, nil
+	}
+}
+
+// Init backs the singleton with an in-process controller, decorated
+// with the middleware chain built from opts.
+func Init(opts ...Option) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	theController = middleware.Chain(controller.New(o.controllerOpts...), o.middlewares...)
+	theACL = o.acl
+	theACLResolver = o.aclResolver
+	theACLToken = o.aclToken
+}
+
+// DialRPC backs the singleton with a remote controller reached over
+// JSON-RPC at addr, as an alternative to Init.
+func DialRPC(addr string) error {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	theController = rpc.NewClient(conn)
+	return nil
+}
+
+// InitGRPC backs the singleton with a remote controller reached over
+// gRPC at target, as an alternative to Init or DialRPC.
+func InitGRPC(target string, opts ...grpc.DialOption) error {
+	client, err := grpcclient.Dial(target, opts...)
+	if err != nil {
+		return err
+	}
+	theController = client
+	return nil
 }
 
-func One(val int) (int, error) {
-	return runMethod(func(c *controller.Controller, arg int) (int, error) {
-		return c.One(arg)
+func One(ctx context.Context, val int) (int, error) {
+	return runMethod(ctx, func(ctx context.Context, c controller.Service, arg int) (int, error) {
+		return c.One(ctx, arg)
 	}, val)
 }
 
-func Two(val string) (string, error) {
-	return runMethod(func(c *controller.Controller, arg string) (string, error) {
-		return c.Two(arg)
+func Two(ctx context.Context, val string) (string, error) {
+	return runMethod(ctx, func(ctx context.Context, c controller.Service, arg string) (string, error) {
+		return c.Two(ctx, arg)
 	}, val)
 }
@@ -1,14 +1,23 @@
 package device_test
 
 import (
-	"github.com/jberkenbilt/go-to-rust-blog/go/device"
+	"bytes"
+	"context"
+	"log"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/acl"
+	"github.com/jberkenbilt/go-to-rust-blog/go/device"
+	"github.com/jberkenbilt/go-to-rust-blog/go/retry"
 )
 
 func TestAll(t *testing.T) {
+	ctx := context.Background()
 	// This is a duplication of the controller test using the wrapper
 	// API.
-	_, err := device.Two("quack")
+	_, err := device.Two(ctx, "quack")
 	if err == nil || err.Error() != "call Init first" {
 		t.Fatalf("wrong error: %s", err)
 	}
@@ -19,18 +28,76 @@ func TestAll(t *testing.T) {
 			t.Fatalf("unexpected error: %s", e)
 		}
 	}
-	seq, err := device.One(5)
+	seq, err := device.One(ctx, 5)
 	checkErr(err)
 	if seq != 1 {
 		t.Fatalf("wrong result: %v", seq)
 	}
-	_, err = device.One(3)
+	_, err = device.One(ctx, 3)
 	if err == nil || err.Error() != "sorry, not that one" {
 		t.Fatalf("wrong error: %s", err)
 	}
-	path, err := device.Two("potato")
+	path, err := device.Two(ctx, "potato")
 	checkErr(err)
 	if path != "two?val=potato&seq=2" {
 		t.Fatalf("wrong result: %v", path)
 	}
 }
+
+func TestInitWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	device.Init(device.WithLogger(log.New(&buf, "", 0)))
+
+	ctx := context.Background()
+	if _, err := device.One(ctx, 5); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(buf.String(), "method=One arg=5 result=1") {
+		t.Fatalf("missing log line: %s", buf.String())
+	}
+}
+
+func TestInitWithRetry(t *testing.T) {
+	device.Init(device.WithRetry(retry.Policy{
+		InitialInterval: time.Millisecond,
+		MaxRetries:      3,
+	}))
+
+	ctx := context.Background()
+	seq, err := device.One(ctx, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if seq != 1 {
+		t.Fatalf("wrong result: %v", seq)
+	}
+}
+
+func TestInitWithACL(t *testing.T) {
+	device.Init(device.WithACL(acl.DenyAll))
+
+	ctx := context.Background()
+	if _, err := device.One(ctx, 5); err != acl.ErrPermissionDenied {
+		t.Fatalf("wrong error: %s", err)
+	}
+}
+
+func TestInitWithToken(t *testing.T) {
+	resolver := acl.ResolverFunc(func(_ context.Context, token string) (acl.ACL, error) {
+		if token == "admin" {
+			return acl.AllowAll, nil
+		}
+		return acl.DenyAll, nil
+	})
+	device.Init(device.WithACLResolver(resolver), device.WithToken("guest"))
+
+	ctx := context.Background()
+	if _, err := device.One(ctx, 5); err != acl.ErrPermissionDenied {
+		t.Fatalf("wrong error: %s", err)
+	}
+
+	device.Init(device.WithACLResolver(resolver), device.WithToken("admin"))
+	if _, err := device.One(ctx, 5); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,66 @@
+package grpcclient_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/grpcclient"
+	"github.com/jberkenbilt/go-to-rust-blog/go/grpcserver"
+	"github.com/jberkenbilt/go-to-rust-blog/go/internal/controller"
+)
+
+func TestAll(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	c := controller.New()
+	srv := grpcserver.New(c, c)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Shutdown()
+
+	client, err := grpcclient.Dial(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %s", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %s", err)
+	}
+
+	seq, err := client.One(ctx, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if seq != 1 {
+		t.Fatalf("wrong result: %v", seq)
+	}
+
+	event := <-events
+	if event.Seq != 1 || event.LastPath != "one?val=5&seq=1" {
+		t.Fatalf("wrong event: %+v", event)
+	}
+
+	_, err = client.One(ctx, 3)
+	if err == nil || err.Error() != "sorry, not that one" {
+		t.Fatalf("wrong error: %s", err)
+	}
+
+	path, err := client.Two(ctx, "potato")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if path != "two?val=potato&seq=2" {
+		t.Fatalf("wrong result: %v", path)
+	}
+}
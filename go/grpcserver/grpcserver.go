@@ -0,0 +1,113 @@
+// Package grpcserver implements controllerpb.ControllerServer against a
+// *controller.Controller, exposing it over gRPC alongside the standard
+// gRPC health-checking service.
+package grpcserver
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/controllerpb"
+	"github.com/jberkenbilt/go-to-rust-blog/go/internal/controller"
+)
+
+const serviceName = "controllerpb.Controller"
+
+// Watcher is satisfied by *controller.Controller. It is kept separate
+// from controller.Service because Subscribe/Unsubscribe aren't part of
+// the cross-cutting-concerns contract that middleware decorates.
+type Watcher interface {
+	Subscribe() (int64, <-chan controller.Event)
+	Unsubscribe(id int64)
+}
+
+// Server adapts a controller.Service and Watcher to
+// controllerpb.ControllerServer.
+type Server struct {
+	controllerpb.UnimplementedControllerServer
+	svc     controller.Service
+	watcher Watcher
+
+	grpcServer   *grpc.Server
+	healthServer *health.Server
+	done         chan struct{}
+}
+
+// New builds a Server that dispatches One/Two to svc and WatchSeq to
+// watcher, ready to be handed to Serve.
+func New(svc controller.Service, watcher Watcher) *Server {
+	s := &Server{svc: svc, watcher: watcher, healthServer: health.NewServer(), done: make(chan struct{})}
+	s.grpcServer = grpc.NewServer()
+	controllerpb.RegisterControllerServer(s.grpcServer, s)
+	grpc_health_v1.RegisterHealthServer(s.grpcServer, s.healthServer)
+	return s
+}
+
+// Serve marks the service healthy and accepts connections on lis until
+// Shutdown is called or Serve returns an error.
+func (s *Server) Serve(lis net.Listener) error {
+	s.healthServer.SetServingStatus(serviceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	return s.grpcServer.Serve(lis)
+}
+
+// Shutdown marks the service as not serving, signals active WatchSeq
+// streams to unsubscribe and return, and gracefully stops the server:
+// in-flight unary calls are allowed to finish, and GracefulStop waits
+// for WatchSeq handlers to observe done and exit, before Shutdown
+// returns.
+func (s *Server) Shutdown() {
+	s.healthServer.SetServingStatus(serviceName, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	close(s.done)
+	s.grpcServer.GracefulStop()
+}
+
+func (s *Server) One(ctx context.Context, req *controllerpb.OneRequest) (*controllerpb.OneResponse, error) {
+	seq, err := s.svc.One(ctx, int(req.Val))
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	return &controllerpb.OneResponse{Seq: int32(seq)}, nil
+}
+
+func (s *Server) Two(ctx context.Context, req *controllerpb.TwoRequest) (*controllerpb.TwoResponse, error) {
+	path, err := s.svc.Two(ctx, req.Val)
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	return &controllerpb.TwoResponse{Path: path}, nil
+}
+
+// WatchSeq streams each Event the controller publishes until the client
+// disconnects, the stream's context is canceled, or the server shuts
+// down. It sends a zero-value ready event as soon as it has
+// subscribed, before any real Event (whose Seq is always >= 1) can be
+// published, so callers can wait for it to avoid racing a mutating
+// call against Subscribe.
+func (s *Server) WatchSeq(_ *controllerpb.WatchSeqRequest, stream controllerpb.Controller_WatchSeqServer) error {
+	id, events := s.watcher.Subscribe()
+	defer s.watcher.Unsubscribe(id)
+	if err := stream.Send(&controllerpb.SeqEvent{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(&controllerpb.SeqEvent{Seq: int32(event.Seq), LastPath: event.LastPath}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server shutting down")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package grpcserver_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/controllerpb"
+	"github.com/jberkenbilt/go-to-rust-blog/go/grpcserver"
+	"github.com/jberkenbilt/go-to-rust-blog/go/internal/controller"
+)
+
+func TestAll(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	c := controller.New()
+	srv := grpcserver.New(c, c)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Shutdown()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %s", err)
+	}
+	defer conn.Close()
+	client := controllerpb.NewControllerClient(conn)
+
+	stream, err := client.WatchSeq(context.Background(), &controllerpb.WatchSeqRequest{})
+	if err != nil {
+		t.Fatalf("WatchSeq: %s", err)
+	}
+	// The server sends a zero-value ready event once it has
+	// subscribed, before any real event (Seq always >= 1); consume it
+	// so the One call below can't race Subscribe.
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("ready: %s", err)
+	}
+
+	resp, err := client.One(context.Background(), &controllerpb.OneRequest{Val: 5})
+	if err != nil {
+		t.Fatalf("One: %s", err)
+	}
+	if resp.Seq != 1 {
+		t.Fatalf("wrong result: %v", resp.Seq)
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %s", err)
+	}
+	if event.Seq != 1 || event.LastPath != "one?val=5&seq=1" {
+		t.Fatalf("wrong event: %+v", event)
+	}
+
+	_, err = client.One(context.Background(), &controllerpb.OneRequest{Val: 3})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+
+	pathResp, err := client.Two(context.Background(), &controllerpb.TwoRequest{Val: "potato"})
+	if err != nil {
+		t.Fatalf("Two: %s", err)
+	}
+	if pathResp.Path != "two?val=potato&seq=2" {
+		t.Fatalf("wrong result: %v", pathResp.Path)
+	}
+}
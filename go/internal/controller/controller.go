@@ -5,51 +5,164 @@
 package controller
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/acl"
+	"github.com/jberkenbilt/go-to-rust-blog/go/retry"
 )
 
+// Service is the contract satisfied by Controller and by any decorators
+// layered on top of it (see the middleware package), so cross-cutting
+// concerns like logging, metrics, and tracing can be added without
+// touching the business logic.
+type Service interface {
+	// One sends a request and returns the sequence of the request.
+	One(ctx context.Context, val int) (int, error)
+	// Two sends a request and returns the path of the request.
+	Two(ctx context.Context, val string) (string, error)
+}
+
+// Event is published to subscribers each time request() commits a new
+// seq/lastPath pair.
+type Event struct {
+	Seq      int
+	LastPath string
+}
+
+// subscriberBuf is the capacity of each subscriber's channel. A slow
+// subscriber that falls this far behind has events dropped rather than
+// blocking other callers of request().
+const subscriberBuf = 16
+
 type Controller struct {
-	lock     sync.RWMutex
-	seq      int
-	lastPath string
+	lock        sync.RWMutex
+	seq         int
+	lastPath    string
+	retryPolicy retry.Policy
+
+	nextSubID   int64
+	subscribers map[int64]chan Event
+}
+
+var _ Service = (*Controller)(nil)
+
+// Option configures a Controller constructed by New.
+type Option func(*Controller)
+
+// WithRetryPolicy sets the retry policy request() uses for its fake
+// network call. The zero Policy, the default, makes a single attempt
+// and never retries.
+func WithRetryPolicy(policy retry.Policy) Option {
+	return func(c *Controller) {
+		c.retryPolicy = policy
+	}
+}
+
+func New(opts ...Option) *Controller {
+	c := &Controller{
+		subscribers: map[int64]chan Event{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-func New() *Controller {
-	return &Controller{}
+// Subscribe registers a new subscriber and returns its ID, for later use
+// with Unsubscribe, and a channel that receives an Event each time
+// request() commits. The channel is buffered; if a subscriber falls too
+// far behind, events are dropped for it rather than blocking commits.
+func (c *Controller) Subscribe() (int64, <-chan Event) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.nextSubID++
+	id := c.nextSubID
+	ch := make(chan Event, subscriberBuf)
+	c.subscribers[id] = ch
+	return id, ch
 }
 
-func (c *Controller) request(path string) error {
+// Unsubscribe removes the subscriber registered under id and closes its
+// channel. It is a no-op if id is not (or is no longer) subscribed.
+func (c *Controller) Unsubscribe(id int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ch, ok := c.subscribers[id]
+	if !ok {
+		return
+	}
+	delete(c.subscribers, id)
+	close(ch)
+}
+
+// request retries a fake network call for path per c.retryPolicy,
+// aborting early if ctx is canceled, and only commits the new
+// seq/lastPath under lock once an attempt ultimately succeeds, so
+// retries never bump seq on their own. It returns the seq/lastPath it
+// committed, since c's fields may already reflect a later call's
+// commit by the time request returns.
+func (c *Controller) request(ctx context.Context, path string) (int, string, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, "", err
+	}
+	err := retry.Do(ctx, c.retryPolicy, func() error {
+		done := make(chan struct{})
+		go func() {
+			// A real implementation would make a network call here.
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return retry.Permanent(ctx.Err())
+		}
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	c.seq++
-	// A real implementation would make a network call here.
 	c.lastPath = fmt.Sprintf("%s&seq=%d", path, c.seq)
-	return nil
+	event := Event{Seq: c.seq, LastPath: c.lastPath}
+	for _, ch := range c.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Subscriber is behind; drop rather than block.
+		}
+	}
+	return event.Seq, event.LastPath, nil
 }
 
 // One sends a request and returns the sequence of the request.
-func (c *Controller) One(val int) (int, error) {
+func (c *Controller) One(ctx context.Context, val int) (int, error) {
+	if !acl.FromContext(ctx).CanCall("One") {
+		return 0, acl.ErrPermissionDenied
+	}
 	if val == 3 {
 		return 0, errors.New("sorry, not that one")
 	}
-	err := c.request(fmt.Sprintf("one?val=%d", val))
+	seq, _, err := c.request(ctx, fmt.Sprintf("one?val=%d", val))
 	if err != nil {
 		return 0, err
 	}
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-	return c.seq, nil
+	return seq, nil
 }
 
 // Two sends a request and returns the path of the request.
-func (c *Controller) Two(val string) (string, error) {
-	err := c.request(fmt.Sprintf("two?val=%s", val))
+func (c *Controller) Two(ctx context.Context, val string) (string, error) {
+	if !acl.FromContext(ctx).CanCall("Two") {
+		return "", acl.ErrPermissionDenied
+	}
+	_, lastPath, err := c.request(ctx, fmt.Sprintf("two?val=%s", val))
 	if err != nil {
 		return "", err
 	}
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-	return c.lastPath, nil
+	return lastPath, nil
 }
@@ -1,11 +1,18 @@
 package controller_test
 
 import (
-	"github.com/jberkenbilt/go-to-rust-blog/go/internal/controller"
+	"context"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/acl"
+	"github.com/jberkenbilt/go-to-rust-blog/go/internal/controller"
+	"github.com/jberkenbilt/go-to-rust-blog/go/retry"
 )
 
 func TestAll(t *testing.T) {
+	ctx := context.Background()
 	c := controller.New()
 	checkErr := func(e error) {
 		t.Helper()
@@ -13,18 +20,120 @@ func TestAll(t *testing.T) {
 			t.Fatalf("unexpected error: %s", e)
 		}
 	}
-	seq, err := c.One(5)
+	seq, err := c.One(ctx, 5)
 	checkErr(err)
 	if seq != 1 {
 		t.Fatalf("wrong result: %v", seq)
 	}
-	_, err = c.One(3)
+	_, err = c.One(ctx, 3)
 	if err == nil || err.Error() != "sorry, not that one" {
 		t.Fatalf("wrong error: %s", err)
 	}
-	path, err := c.Two("potato")
+	path, err := c.Two(ctx, "potato")
 	checkErr(err)
 	if path != "two?val=potato&seq=2" {
 		t.Fatalf("wrong result: %v", path)
 	}
 }
+
+func TestSubscribe(t *testing.T) {
+	ctx := context.Background()
+	c := controller.New()
+	id, events := c.Subscribe()
+	if _, err := c.One(ctx, 5); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	select {
+	case e := <-events:
+		if e.Seq != 1 || e.LastPath != "one?val=5&seq=1" {
+			t.Fatalf("wrong event: %+v", e)
+		}
+	default:
+		t.Fatalf("expected an event")
+	}
+	c.Unsubscribe(id)
+	if _, ok := <-events; ok {
+		t.Fatalf("expected channel to be closed after Unsubscribe")
+	}
+}
+
+func TestRequestCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := controller.New()
+	if _, err := c.One(ctx, 5); err != context.Canceled {
+		t.Fatalf("wrong error: %s", err)
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	ctx := context.Background()
+	c := controller.New(controller.WithRetryPolicy(retry.Policy{
+		InitialInterval: time.Millisecond,
+		MaxRetries:      3,
+	}))
+	seq, err := c.One(ctx, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if seq != 1 {
+		t.Fatalf("wrong result: %v", seq)
+	}
+}
+
+// TestOneReturnsItsOwnSeqUnderConcurrency guards against request()
+// re-reading c.seq after releasing the write lock: if it did, a caller
+// could observe a later caller's commit instead of its own.
+func TestOneReturnsItsOwnSeqUnderConcurrency(t *testing.T) {
+	ctx := context.Background()
+	c := controller.New()
+	const n = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := map[int]int{}
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			seq, err := c.One(ctx, 5)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			mu.Lock()
+			seen[seq]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if len(seen) != n {
+		t.Fatalf("expected %d distinct seqs, got %d: %v", n, len(seen), seen)
+	}
+	for seq, count := range seen {
+		if count != 1 {
+			t.Fatalf("seq %d was returned to %d callers", seq, count)
+		}
+	}
+}
+
+func TestACLDeniesCall(t *testing.T) {
+	ctx := acl.WithACL(context.Background(), acl.DenyAll)
+	c := controller.New()
+	if _, err := c.One(ctx, 5); err != acl.ErrPermissionDenied {
+		t.Fatalf("wrong error: %s", err)
+	}
+	if _, err := c.Two(ctx, "potato"); err != acl.ErrPermissionDenied {
+		t.Fatalf("wrong error: %s", err)
+	}
+}
+
+func TestACLAllowsSpecificMethod(t *testing.T) {
+	ctx := acl.WithACL(context.Background(), acl.StaticACL{Allowed: map[string]bool{"One": true}})
+	c := controller.New()
+	if _, err := c.One(ctx, 5); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.Two(ctx, "potato"); err != acl.ErrPermissionDenied {
+		t.Fatalf("wrong error: %s", err)
+	}
+}
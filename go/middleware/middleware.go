@@ -0,0 +1,152 @@
+// Package middleware provides go-kit-style decorators for
+// controller.Service: composable wrappers that add logging, metrics, or
+// tracing around One/Two without touching the business logic.
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/internal/controller"
+)
+
+// Middleware wraps a Service with a cross-cutting concern, producing
+// another Service with the same contract.
+type Middleware func(controller.Service) controller.Service
+
+// Chain applies mw to svc in order, so the first middleware is
+// outermost: it sees each call first and its result last.
+func Chain(svc controller.Service, mw ...Middleware) controller.Service {
+	for i := len(mw) - 1; i >= 0; i-- {
+		svc = mw[i](svc)
+	}
+	return svc
+}
+
+type loggingMiddleware struct {
+	logger *log.Logger
+	next   controller.Service
+}
+
+var _ controller.Service = (*loggingMiddleware)(nil)
+
+// LoggingMiddleware logs method, args, result, err, and duration for
+// every call as structured key/value pairs.
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	return func(next controller.Service) controller.Service {
+		return &loggingMiddleware{logger: logger, next: next}
+	}
+}
+
+func (mw *loggingMiddleware) One(ctx context.Context, val int) (result int, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Printf("method=One arg=%d result=%d err=%v duration=%s", val, result, err, time.Since(begin))
+	}(time.Now())
+	return mw.next.One(ctx, val)
+}
+
+func (mw *loggingMiddleware) Two(ctx context.Context, val string) (result string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Printf("method=Two arg=%s result=%s err=%v duration=%s", val, result, err, time.Since(begin))
+	}(time.Now())
+	return mw.next.Two(ctx, val)
+}
+
+type instrumentingMiddleware struct {
+	requestCount   *prometheus.CounterVec
+	requestLatency *prometheus.HistogramVec
+	seqGauge       prometheus.Gauge
+	next           controller.Service
+}
+
+var _ controller.Service = (*instrumentingMiddleware)(nil)
+
+// InstrumentingMiddleware records requestCount and requestLatency,
+// labeled by method and outcome ("success" or "error"), and sets
+// seqGauge to the latest sequence number returned by One.
+func InstrumentingMiddleware(
+	requestCount *prometheus.CounterVec,
+	requestLatency *prometheus.HistogramVec,
+	seqGauge prometheus.Gauge,
+) Middleware {
+	return func(next controller.Service) controller.Service {
+		return &instrumentingMiddleware{
+			requestCount:   requestCount,
+			requestLatency: requestLatency,
+			seqGauge:       seqGauge,
+			next:           next,
+		}
+	}
+}
+
+func (mw *instrumentingMiddleware) One(ctx context.Context, val int) (int, error) {
+	begin := time.Now()
+	seq, err := mw.next.One(ctx, val)
+	mw.observe("One", begin, err)
+	if err == nil {
+		mw.seqGauge.Set(float64(seq))
+	}
+	return seq, err
+}
+
+func (mw *instrumentingMiddleware) Two(ctx context.Context, val string) (string, error) {
+	begin := time.Now()
+	path, err := mw.next.Two(ctx, val)
+	mw.observe("Two", begin, err)
+	return path, err
+}
+
+func (mw *instrumentingMiddleware) observe(method string, begin time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	mw.requestCount.WithLabelValues(method, outcome).Inc()
+	mw.requestLatency.WithLabelValues(method, outcome).Observe(time.Since(begin).Seconds())
+}
+
+type tracingMiddleware struct {
+	tracer trace.Tracer
+	next   controller.Service
+}
+
+var _ controller.Service = (*tracingMiddleware)(nil)
+
+// TracingMiddleware opens an OpenTelemetry span per call and records the
+// computed path as a span attribute.
+func TracingMiddleware(tracer trace.Tracer) Middleware {
+	return func(next controller.Service) controller.Service {
+		return &tracingMiddleware{tracer: tracer, next: next}
+	}
+}
+
+func (mw *tracingMiddleware) One(ctx context.Context, val int) (int, error) {
+	ctx, span := mw.tracer.Start(ctx, "Controller.One")
+	defer span.End()
+	span.SetAttributes(attribute.String("path", fmt.Sprintf("one?val=%d", val)))
+	seq, err := mw.next.One(ctx, val)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return seq, err
+}
+
+func (mw *tracingMiddleware) Two(ctx context.Context, val string) (string, error) {
+	ctx, span := mw.tracer.Start(ctx, "Controller.Two")
+	defer span.End()
+	path, err := mw.next.Two(ctx, val)
+	span.SetAttributes(attribute.String("path", path))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return path, err
+}
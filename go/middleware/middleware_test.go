@@ -0,0 +1,37 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/middleware"
+)
+
+type fakeService struct{}
+
+func (fakeService) One(_ context.Context, val int) (int, error) {
+	return val + 1, nil
+}
+
+func (fakeService) Two(_ context.Context, val string) (string, error) {
+	return "two?val=" + val, nil
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	svc := middleware.Chain(fakeService{}, middleware.LoggingMiddleware(log.New(&buf, "", 0)))
+
+	seq, err := svc.One(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if seq != 6 {
+		t.Fatalf("wrong result: %v", seq)
+	}
+	if !strings.Contains(buf.String(), "method=One arg=5 result=6") {
+		t.Fatalf("missing log line: %s", buf.String())
+	}
+}
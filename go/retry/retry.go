@@ -0,0 +1,140 @@
+// Package retry implements a configurable exponential-backoff retry
+// policy, modeled after github.com/cenkalti/backoff, for operations
+// like controller.Controller.request that may need to retry a flaky
+// call.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// Policy configures how Do retries a failing operation. The zero
+// Policy makes a single attempt and never retries, so existing callers
+// that don't configure a Policy keep today's behavior.
+type Policy struct {
+	// InitialInterval is the delay before the first retry.
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between retries. Zero means
+	// uncapped.
+	MaxInterval time.Duration
+	// Multiplier grows the interval after each retry. Zero is
+	// treated as 1.5, matching cenkalti/backoff's default.
+	Multiplier float64
+	// RandomizationFactor jitters each interval by
+	// interval * (1 ± RandomizationFactor*rand.Float64()). Zero
+	// disables jitter.
+	RandomizationFactor float64
+	// MaxElapsedTime bounds the total time spent retrying, measured
+	// from the first attempt. Zero means uncapped.
+	MaxElapsedTime time.Duration
+	// MaxRetries caps the number of retries after the first attempt.
+	// Zero means no retries.
+	MaxRetries int
+}
+
+// permanentError marks err as non-retryable.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so Do stops retrying and returns err immediately,
+// unwrapped. A nil err returns nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Clock abstracts time so Do's timing can be driven deterministically
+// in tests; production code should use SystemClock.
+type Clock interface {
+	Now() time.Time
+	// Sleep blocks for d or until ctx is done, whichever comes
+	// first.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// SystemClock is the default Clock, backed by the real time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Do calls op, retrying per policy until it succeeds, returns a
+// Permanent error, ctx is canceled, or the policy's retry budget
+// (MaxRetries/MaxElapsedTime) is exhausted. It sleeps between attempts
+// using SystemClock; use DoWithClock to inject a fake clock in tests.
+func Do(ctx context.Context, policy Policy, op func() error) error {
+	return DoWithClock(ctx, policy, SystemClock, op)
+}
+
+// DoWithClock is Do, but sourcing time and sleeps from clock instead of
+// SystemClock.
+func DoWithClock(ctx context.Context, policy Policy, clock Clock, op func() error) error {
+	multiplier := policy.Multiplier
+	if multiplier == 0 {
+		multiplier = 1.5
+	}
+	start := clock.Now()
+	interval := policy.InitialInterval
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt >= policy.MaxRetries {
+			return err
+		}
+		if policy.MaxElapsedTime > 0 && clock.Now().Sub(start) >= policy.MaxElapsedTime {
+			return err
+		}
+		if sleepErr := clock.Sleep(ctx, jitter(interval, policy.RandomizationFactor)); sleepErr != nil {
+			return sleepErr
+		}
+		interval = time.Duration(float64(interval) * multiplier)
+		if policy.MaxInterval > 0 && interval > policy.MaxInterval {
+			interval = policy.MaxInterval
+		}
+	}
+}
+
+// jitter scales interval by a random factor in
+// [1-randomizationFactor, 1+randomizationFactor].
+func jitter(interval time.Duration, randomizationFactor float64) time.Duration {
+	if randomizationFactor <= 0 || interval <= 0 {
+		return interval
+	}
+	delta := randomizationFactor * float64(interval)
+	min := float64(interval) - delta
+	max := float64(interval) + delta
+	return time.Duration(min + rand.Float64()*(max-min))
+}
@@ -0,0 +1,153 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/retry"
+)
+
+// fakeClock advances instantly on Sleep instead of actually waiting, so
+// tests observe retry timing without running in real time.
+type fakeClock struct {
+	now     time.Time
+	sleeps  []time.Duration
+	elapsed time.Duration
+}
+
+func (c *fakeClock) Now() time.Time { return c.now.Add(c.elapsed) }
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.sleeps = append(c.sleeps, d)
+	c.elapsed += d
+	return nil
+}
+
+func TestDoWithClockRetriesUntilSuccess(t *testing.T) {
+	clock := &fakeClock{}
+	attempts := 0
+	err := retry.DoWithClock(context.Background(), retry.Policy{
+		InitialInterval: time.Second,
+		Multiplier:      2,
+		MaxRetries:      5,
+	}, clock, func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("wrong attempt count: %d", attempts)
+	}
+	if len(clock.sleeps) != 2 {
+		t.Fatalf("wrong sleep count: %d", len(clock.sleeps))
+	}
+	if clock.sleeps[0] != time.Second || clock.sleeps[1] != 2*time.Second {
+		t.Fatalf("wrong backoff sequence: %v", clock.sleeps)
+	}
+}
+
+func TestDoWithClockMaxRetriesExhausted(t *testing.T) {
+	clock := &fakeClock{}
+	wantErr := errors.New("still failing")
+	attempts := 0
+	err := retry.DoWithClock(context.Background(), retry.Policy{
+		InitialInterval: time.Millisecond,
+		MaxRetries:      2,
+	}, clock, func() error {
+		attempts++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("wrong error: %s", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("wrong attempt count: %d", attempts)
+	}
+}
+
+func TestDoWithClockMaxElapsedTime(t *testing.T) {
+	clock := &fakeClock{}
+	attempts := 0
+	err := retry.DoWithClock(context.Background(), retry.Policy{
+		InitialInterval: time.Second,
+		MaxRetries:      100,
+		MaxElapsedTime:  time.Second,
+	}, clock, func() error {
+		attempts++
+		return errors.New("nope")
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts != 2 {
+		t.Fatalf("wrong attempt count: %d", attempts)
+	}
+}
+
+func TestDoWithClockPermanentStopsImmediately(t *testing.T) {
+	clock := &fakeClock{}
+	wantErr := errors.New("fatal")
+	attempts := 0
+	err := retry.DoWithClock(context.Background(), retry.Policy{
+		InitialInterval: time.Second,
+		MaxRetries:      5,
+	}, clock, func() error {
+		attempts++
+		return retry.Permanent(wantErr)
+	})
+	if err != wantErr {
+		t.Fatalf("wrong error: %s", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("wrong attempt count: %d", attempts)
+	}
+}
+
+func TestDoWithClockContextCanceled(t *testing.T) {
+	clock := &fakeClock{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts := 0
+	err := retry.DoWithClock(ctx, retry.Policy{
+		InitialInterval: time.Second,
+		MaxRetries:      5,
+	}, clock, func() error {
+		attempts++
+		return errors.New("nope")
+	})
+	if err != context.Canceled {
+		t.Fatalf("wrong error: %s", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("wrong attempt count: %d", attempts)
+	}
+}
+
+func TestZeroPolicyMakesOneAttempt(t *testing.T) {
+	clock := &fakeClock{}
+	attempts := 0
+	wantErr := errors.New("nope")
+	err := retry.DoWithClock(context.Background(), retry.Policy{}, clock, func() error {
+		attempts++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("wrong error: %s", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("wrong attempt count: %d", attempts)
+	}
+	if len(clock.sleeps) != 0 {
+		t.Fatalf("expected no sleeps, got %v", clock.sleeps)
+	}
+}
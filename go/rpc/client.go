@@ -0,0 +1,175 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// Client issues JSON-RPC 2.0 calls over a net.Conn and correlates
+// responses to the request that triggered them by ID. A single Client
+// may have many calls in flight concurrently.
+type Client struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+	nextID  int64
+
+	pendingMu sync.Mutex
+	pending   map[int64]chan *response
+}
+
+// NewClient wraps conn and starts the background goroutine that reads
+// responses. The caller remains responsible for closing conn.
+func NewClient(conn net.Conn) *Client {
+	c := &Client{
+		conn:    conn,
+		pending: map[int64]chan *response{},
+	}
+	go c.readLoop()
+	return c
+}
+
+func (c *Client) readLoop() {
+	fr := newFrameReader(c.conn)
+	for {
+		body, err := fr.readMessage()
+		if err != nil {
+			c.failAllPending(err)
+			return
+		}
+		var resp response
+		if err := json.Unmarshal(body, &resp); err != nil {
+			continue
+		}
+		if resp.ID == nil {
+			continue
+		}
+		var id int64
+		if err := json.Unmarshal(*resp.ID, &id); err != nil {
+			continue
+		}
+		c.pendingMu.Lock()
+		ch, ok := c.pending[id]
+		delete(c.pending, id)
+		c.pendingMu.Unlock()
+		if ok {
+			ch <- &resp
+		}
+	}
+}
+
+func (c *Client) failAllPending(err error) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	for id, ch := range c.pending {
+		ch <- &response{Error: &rpcError{Code: CodeInternalError, Message: err.Error()}}
+		delete(c.pending, id)
+	}
+}
+
+// Call invokes method with params and decodes the result into result,
+// which must be a pointer. If ctx is canceled before the response
+// arrives, Call sends a "$/cancelRequest" notification for the
+// in-flight request and returns ctx.Err().
+func (c *Client) Call(ctx context.Context, method string, params any, result any) error {
+	id := atomic.AddInt64(&c.nextID, 1)
+	rawID := mustMarshal(id)
+	idMsg := json.RawMessage(rawID)
+
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req := request{JSONRPC: jsonrpcVersion, ID: &idMsg, Method: method, Params: rawParams}
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	ch := make(chan *response, 1)
+	c.pendingMu.Lock()
+	c.pending[id] = ch
+	c.pendingMu.Unlock()
+
+	c.writeMu.Lock()
+	err = writeMessage(c.conn, encoded)
+	c.writeMu.Unlock()
+	if err != nil {
+		c.pendingMu.Lock()
+		delete(c.pending, id)
+		c.pendingMu.Unlock()
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return &Error{Code: resp.Error.Code, Message: resp.Error.Message}
+		}
+		if result == nil || len(resp.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	case <-ctx.Done():
+		c.pendingMu.Lock()
+		delete(c.pending, id)
+		c.pendingMu.Unlock()
+		c.sendCancel(idMsg)
+		return ctx.Err()
+	}
+}
+
+func (c *Client) sendCancel(id json.RawMessage) {
+	notif := request{
+		JSONRPC: jsonrpcVersion,
+		Method:  "$/cancelRequest",
+		Params:  mustMarshal(cancelParams{ID: id}),
+	}
+	encoded, err := json.Marshal(notif)
+	if err != nil {
+		return
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = writeMessage(c.conn, encoded)
+}
+
+// One calls the "controller.One" method, mirroring
+// controller.Service.One's signature so *Client satisfies
+// controller.Service.
+func (c *Client) One(ctx context.Context, val int) (int, error) {
+	var res oneResult
+	if err := c.Call(ctx, "controller.One", oneParams{Val: val}, &res); err != nil {
+		return 0, unwrapControllerErr(err)
+	}
+	return res.Seq, nil
+}
+
+// Two calls the "controller.Two" method, mirroring
+// controller.Service.Two's signature so *Client satisfies
+// controller.Service.
+func (c *Client) Two(ctx context.Context, val string) (string, error) {
+	var res twoResult
+	if err := c.Call(ctx, "controller.Two", twoParams{Val: val}, &res); err != nil {
+		return "", unwrapControllerErr(err)
+	}
+	return res.Path, nil
+}
+
+// unwrapControllerErr turns a CodeControllerErr rpc.Error back into a
+// plain error carrying the original controller message, so callers that
+// compare err.Error() against "sorry, not that one" keep working
+// whether the controller is local or remote.
+func unwrapControllerErr(err error) error {
+	var rpcErr *Error
+	if e, ok := err.(*Error); ok && e.Code == CodeControllerErr {
+		rpcErr = e
+	}
+	if rpcErr == nil {
+		return err
+	}
+	return fmt.Errorf("%s", rpcErr.Message)
+}
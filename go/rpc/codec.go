@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// frameReader reads JSON-RPC messages from a stream that may use either
+// LSP-style "Content-Length:" framing or plain newline-delimited JSON,
+// one message per line. The mode is detected per read: a line starting
+// with "Content-Length:" is treated as the start of a header block,
+// anything else is treated as a complete JSON message on its own line.
+type frameReader struct {
+	r *bufio.Reader
+}
+
+func newFrameReader(r io.Reader) *frameReader {
+	return &frameReader{r: bufio.NewReader(r)}
+}
+
+func (fr *frameReader) readMessage() ([]byte, error) {
+	line, err := fr.r.ReadString('\n')
+	if err != nil && line == "" {
+		return nil, err
+	}
+	trimmed := strings.TrimRight(line, "\r\n")
+	if !strings.HasPrefix(trimmed, "Content-Length:") {
+		return []byte(trimmed), nil
+	}
+	length, err := parseContentLength(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	// Consume remaining headers up to the blank line separator.
+	for {
+		h, err := fr.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimRight(h, "\r\n") == "" {
+			break
+		}
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(fr.r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func parseContentLength(header string) (int, error) {
+	parts := strings.SplitN(header, ":", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("malformed Content-Length header: %q", header)
+	}
+	return strconv.Atoi(strings.TrimSpace(parts[1]))
+}
+
+// writeMessage frames body using LSP-style Content-Length headers, which
+// a frameReader can always decode regardless of which mode the peer
+// prefers for reading.
+func writeMessage(w io.Writer, body []byte) error {
+	_, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
@@ -0,0 +1,83 @@
+// Package rpc exposes internal/controller.Controller over JSON-RPC 2.0
+// (https://www.jsonrpc.org/specification), so a remote process can drive
+// it without importing the Go types. It provides both a Server that
+// dispatches incoming requests to a controller.Service (such as a
+// *controller.Controller) and a Client
+// that issues calls and correlates responses by request ID.
+package rpc
+
+import "encoding/json"
+
+const jsonrpcVersion = "2.0"
+
+// Standard JSON-RPC 2.0 error codes, plus an application-specific range
+// (-32000 to -32099) reserved for errors returned by the controller
+// itself.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeControllerErr  = -32000
+)
+
+// request is the wire format of a JSON-RPC request or notification. A
+// nil ID marks a notification, which gets no response.
+type request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+// response is the wire format of a JSON-RPC response. Exactly one of
+// Result or Error is set.
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *rpcError        `json:"error,omitempty"`
+}
+
+// rpcError is the wire format of a JSON-RPC error object.
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error adapts an rpcError returned by the server into a Go error that
+// callers of Client.Call can inspect for Code.
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// cancelParams is the payload of the "$/cancelRequest" notification a
+// Client sends when its caller's context is canceled.
+type cancelParams struct {
+	ID json.RawMessage `json:"id"`
+}
+
+// oneParams/oneResult and twoParams/twoResult mirror the signatures of
+// controller.Controller.One and controller.Controller.Two.
+type oneParams struct {
+	Val int `json:"val"`
+}
+
+type oneResult struct {
+	Seq int `json:"seq"`
+}
+
+type twoParams struct {
+	Val string `json:"val"`
+}
+
+type twoResult struct {
+	Path string `json:"path"`
+}
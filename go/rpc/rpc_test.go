@@ -0,0 +1,106 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/internal/controller"
+	"github.com/jberkenbilt/go-to-rust-blog/go/rpc"
+)
+
+func TestAll(t *testing.T) {
+	ctx := context.Background()
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	srv := rpc.NewServer(controller.New())
+	go func() { _ = srv.Serve(serverConn) }()
+
+	client := rpc.NewClient(clientConn)
+	checkErr := func(e error) {
+		t.Helper()
+		if e != nil {
+			t.Fatalf("unexpected error: %s", e)
+		}
+	}
+
+	seq, err := client.One(ctx, 5)
+	checkErr(err)
+	if seq != 1 {
+		t.Fatalf("wrong result: %v", seq)
+	}
+
+	_, err = client.One(ctx, 3)
+	if err == nil || err.Error() != "sorry, not that one" {
+		t.Fatalf("wrong error: %s", err)
+	}
+
+	path, err := client.Two(ctx, "potato")
+	checkErr(err)
+	if path != "two?val=potato&seq=2" {
+		t.Fatalf("wrong result: %v", path)
+	}
+
+	var zero int
+	err = client.Call(context.Background(), "controller.NoSuchMethod", struct{}{}, &zero)
+	if err == nil {
+		t.Fatalf("expected error calling unknown method")
+	}
+	if e, ok := err.(*rpc.Error); !ok || e.Code != rpc.CodeMethodNotFound {
+		t.Fatalf("wrong error: %s", err)
+	}
+}
+
+// blockingService never completes One/Two on its own; it only returns
+// once ctx is canceled, closing unblocked so a test can observe that
+// the cancellation actually reached the server-side handler.
+type blockingService struct {
+	unblocked chan struct{}
+}
+
+func (s blockingService) One(ctx context.Context, _ int) (int, error) {
+	<-ctx.Done()
+	close(s.unblocked)
+	return 0, ctx.Err()
+}
+
+func (s blockingService) Two(ctx context.Context, _ string) (string, error) {
+	<-ctx.Done()
+	close(s.unblocked)
+	return "", ctx.Err()
+}
+
+func TestCancelRequestAbortsInFlightCall(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	unblocked := make(chan struct{})
+	srv := rpc.NewServer(blockingService{unblocked: unblocked})
+	go func() { _ = srv.Serve(serverConn) }()
+
+	client := rpc.NewClient(clientConn)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	callDone := make(chan error, 1)
+	go func() {
+		var zero int
+		callDone <- client.Call(ctx, "controller.One", struct {
+			Val int `json:"val"`
+		}{Val: 5}, &zero)
+	}()
+
+	cancel()
+
+	select {
+	case <-unblocked:
+	case <-time.After(time.Second):
+		t.Fatal("server-side handler was not canceled by $/cancelRequest")
+	}
+	if err := <-callDone; err != context.Canceled {
+		t.Fatalf("wrong error: %s", err)
+	}
+}
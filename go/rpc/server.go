@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/jberkenbilt/go-to-rust-blog/go/internal/controller"
+)
+
+// Server dispatches JSON-RPC 2.0 requests to a controller.Service. A
+// single Server can serve many connections concurrently; each call to
+// Serve handles one connection until it is closed or returns an error.
+type Server struct {
+	svc controller.Service
+
+	cancelMu sync.Mutex
+	cancel   map[string]context.CancelFunc
+}
+
+// NewServer returns a Server that dispatches to svc.
+func NewServer(svc controller.Service) *Server {
+	return &Server{svc: svc, cancel: map[string]context.CancelFunc{}}
+}
+
+// Serve reads framed JSON-RPC requests from conn, dispatches them, and
+// writes back responses until conn is closed or a read/write error
+// occurs. It blocks until then, so callers typically run it in a
+// goroutine per accepted connection.
+func (s *Server) Serve(conn net.Conn) error {
+	fr := newFrameReader(conn)
+	var writeMu sync.Mutex
+	for {
+		body, err := fr.readMessage()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if len(body) == 0 {
+			continue
+		}
+		go func(body []byte) {
+			resp := s.handle(body)
+			if resp == nil {
+				// Notification: no response is sent.
+				return
+			}
+			encoded, err := json.Marshal(resp)
+			if err != nil {
+				return
+			}
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			_ = writeMessage(conn, encoded)
+		}(body)
+	}
+}
+
+// handle decodes and dispatches a single JSON-RPC message, returning the
+// response to send, or nil if the message was a notification (no ID).
+// Each request with an ID gets a cancelable context registered under
+// its ID, so a "$/cancelRequest" notification arriving on another
+// goroutine (requests are dispatched concurrently, see Serve) can abort
+// it.
+func (s *Server) handle(body []byte) *response {
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return errResponse(nil, CodeParseError, err.Error())
+	}
+	if req.Method == "$/cancelRequest" {
+		s.cancelInFlight(req.Params)
+		return nil
+	}
+	if req.ID == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	key := string(*req.ID)
+	s.cancelMu.Lock()
+	s.cancel[key] = cancel
+	s.cancelMu.Unlock()
+	defer func() {
+		s.cancelMu.Lock()
+		delete(s.cancel, key)
+		s.cancelMu.Unlock()
+		cancel()
+	}()
+
+	result, rpcErr := s.dispatch(ctx, req.Method, req.Params)
+	if rpcErr != nil {
+		return errResponse(req.ID, rpcErr.Code, rpcErr.Message)
+	}
+	return &response{JSONRPC: jsonrpcVersion, ID: req.ID, Result: result}
+}
+
+// cancelInFlight cancels the context of the in-flight request named by
+// params' "id" field, if any; it is a no-op if that request has already
+// finished or never existed.
+func (s *Server) cancelInFlight(params json.RawMessage) {
+	var p cancelParams
+	if err := json.Unmarshal(params, &p); err != nil || p.ID == nil {
+		return
+	}
+	key := string(p.ID)
+	s.cancelMu.Lock()
+	cancel, ok := s.cancel[key]
+	s.cancelMu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func (s *Server) dispatch(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, *Error) {
+	switch method {
+	case "controller.One":
+		var p oneParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+		seq, err := s.svc.One(ctx, p.Val)
+		if err != nil {
+			return nil, &Error{Code: CodeControllerErr, Message: err.Error()}
+		}
+		return mustMarshal(oneResult{Seq: seq}), nil
+	case "controller.Two":
+		var p twoParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+		path, err := s.svc.Two(ctx, p.Val)
+		if err != nil {
+			return nil, &Error{Code: CodeControllerErr, Message: err.Error()}
+		}
+		return mustMarshal(twoResult{Path: path}), nil
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+func errResponse(id *json.RawMessage, code int, message string) *response {
+	return &response{
+		JSONRPC: jsonrpcVersion,
+		ID:      id,
+		Error:   &rpcError{Code: code, Message: message},
+	}
+}
+
+func mustMarshal(v any) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}